@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ShredMode selects the destruction strategy Shred uses for a file.
+type ShredMode int
+
+const (
+	// ModeOverwrite applies s.Patterns in place, the original behavior.
+	ModeOverwrite ShredMode = iota
+	// ModeCrypto always uses CryptoShred instead of overwriting.
+	ModeCrypto
+	// ModeAuto inspects the filesystem each file lives on and uses
+	// CryptoShred on copy-on-write/log-structured filesystems, where
+	// overwriteFile's in-place passes cannot guarantee the original
+	// blocks are touched, falling back to ModeOverwrite everywhere else.
+	ModeAuto
+)
+
+// CryptoShred destroys path's content without relying on overwriting the
+// specific disk blocks that hold it: it streams the file through
+// AES-256-CTR keyed from s.Rand, writes the ciphertext back over the
+// original plaintext in a single pass, fsyncs, discards the key, and
+// removes the file. Unlike repeated overwrite passes, this is effective on
+// copy-on-write filesystems (Btrfs, ZFS), log-structured ones (F2FS) and
+// similar cases where a write may be redirected to a new block instead of
+// landing on the one being overwritten: even if the plaintext blocks
+// survive on disk, they're unrecoverable without the discarded key.
+//
+// CryptoShred is equivalent to calling the context-aware version with
+// context.Background().
+func (s *Shredder) CryptoShred(path string) error {
+	return s.cryptoShred(context.Background(), path)
+}
+
+func (s *Shredder) cryptoShred(ctx context.Context, path string) error {
+	info, err := s.FS.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("shred: %s is not a regular file", path)
+	}
+	size := info.Size()
+	if size < 0 {
+		return fmt.Errorf("shred: invalid file size for %s", path)
+	}
+
+	var key [32]byte
+	if _, err := io.ReadFull(s.Rand, key[:]); err != nil {
+		return fmt.Errorf("generate key: %w", err)
+	}
+	defer zero(key[:])
+
+	var iv [aes.BlockSize]byte
+	if _, err := io.ReadFull(s.Rand, iv[:]); err != nil {
+		return fmt.Errorf("generate iv: %w", err)
+	}
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return fmt.Errorf("aes cipher: %w", err)
+	}
+	stream := cipher.NewCTR(block, iv[:])
+
+	f, err := s.FS.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	buf := make([]byte, s.BufSize)
+	var pos int64
+	for pos < size {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunk := buf
+		remaining := size - pos
+		if remaining < int64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+
+		if _, err := io.ReadFull(f, chunk); err != nil {
+			return fmt.Errorf("read at offset %d: %w", pos, err)
+		}
+		if _, err := f.Seek(pos, io.SeekStart); err != nil {
+			return fmt.Errorf("seek %s: %w", path, err)
+		}
+
+		stream.XORKeyStream(chunk, chunk)
+
+		n, err := f.Write(chunk)
+		if err != nil {
+			return fmt.Errorf("write at offset %d: %w", pos, err)
+		}
+		pos += int64(n)
+
+		if s.Progress != nil {
+			s.Progress(path, pos, size, 1, 1)
+		}
+	}
+
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("sync %s: %w", path, err)
+	}
+
+	if err := s.FS.Remove(path); err != nil {
+		return fmt.Errorf("remove %s: %w", path, err)
+	}
+	return nil
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCryptoShredChangesContentAndRemoves(t *testing.T) {
+	fs := newMemFS()
+	original := bytes.Repeat([]byte("secret"), 100)
+	fs.addFile("f.bin", original, 0o600)
+
+	s := NewShredder()
+	s.FS = fs
+
+	err := s.CryptoShred("f.bin")
+	assert.NoError(t, err, "CryptoShred should succeed")
+
+	_, statErr := fs.Stat("f.bin")
+	assert.Error(t, statErr, "file should be removed after CryptoShred")
+}
+
+// keepFS wraps memFS but makes Remove a no-op, so a test can inspect the
+// ciphertext CryptoShred leaves behind before its final unlink.
+type keepFS struct {
+	*memFS
+}
+
+func (keepFS) Remove(name string) error { return nil }
+
+func TestCryptoShredOverwritesBeforeRemoving(t *testing.T) {
+	mem := newMemFS()
+	original := bytes.Repeat([]byte{0x00}, 256)
+	mem.addFile("f.bin", original, 0o600)
+
+	s := NewShredder()
+	s.FS = keepFS{mem}
+
+	err := s.CryptoShred("f.bin")
+	assert.NoError(t, err, "CryptoShred should succeed")
+	assert.NotEqual(t, original, mem.entries["f.bin"].data, "ciphertext should differ from the all-zero plaintext")
+}
+
+func TestModeAutoSwitchesToCryptoOnCOWFilesystem(t *testing.T) {
+	fs := newMemFS()
+	fs.fsType = FSTypeBtrfs
+	fs.addFile("f.bin", []byte("data"), 0o600)
+
+	s := NewShredder()
+	s.FS = fs
+	s.Mode = ModeAuto
+
+	err := s.Shred("f.bin")
+	assert.NoError(t, err, "auto mode should crypto-shred on btrfs without error")
+	_, statErr := fs.Stat("f.bin")
+	assert.Error(t, statErr, "file should be removed")
+}
+
+func TestModeAutoFallsBackToOverwriteOnUnknownFilesystem(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("f.bin", []byte("data"), 0o600)
+
+	s := newTestShredder(fs, 0xAA)
+	s.Mode = ModeAuto
+
+	err := s.Shred("f.bin")
+	assert.NoError(t, err, "auto mode should overwrite-shred on an unknown filesystem")
+}
+
+func TestRequiresCryptoShred(t *testing.T) {
+	assert.True(t, requiresCryptoShred(FSTypeBtrfs))
+	assert.True(t, requiresCryptoShred(FSTypeZFS))
+	assert.True(t, requiresCryptoShred(FSTypeF2FS))
+	assert.True(t, requiresCryptoShred(FSTypeTmpfs))
+	assert.True(t, requiresCryptoShred(FSTypeOverlay))
+	assert.True(t, requiresCryptoShred(FSTypeNFS))
+	assert.False(t, requiresCryptoShred(FSTypeUnknown))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOverwriteFileDeviceRequiresOSFilesystem verifies that overwriteFile
+// rejects a device entry on a non-OS-backed FS: the default DeviceSize
+// (deviceSizeViaIoctl) only works against a real *os.File, and memFS's
+// files never are one.
+func TestOverwriteFileDeviceRequiresOSFilesystem(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("dev/fake0", make([]byte, 16), os.ModeDevice|0o600)
+
+	s := newTestShredder(fs, 0x00)
+	err := s.overwriteFile(context.Background(), "dev/fake0")
+	assert.ErrorContains(t, err, "requires the real OS filesystem")
+}
+
+// TestShredFileNeverRemovesDeviceNode simulates a device entry on memFS by
+// overriding DeviceSize, since memFS can't back a real block device. It
+// confirms shredFile overwrites the device's content but, unlike a regular
+// file, leaves the node itself in place instead of unlinking it.
+func TestShredFileNeverRemovesDeviceNode(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("dev/fake0", bytes8(0xAA), os.ModeDevice|0o600)
+
+	s := newTestShredder(fs, 0x00)
+	s.Patterns = []PassPattern{Fixed(0x55)}
+	s.DeviceSize = func(f File) (int64, error) { return 8, nil }
+
+	err := s.Shred("dev/fake0")
+	assert.NoError(t, err)
+
+	info, statErr := fs.Stat("dev/fake0")
+	if assert.NoError(t, statErr, "device node should still exist after Shred") {
+		assert.True(t, info.Mode()&os.ModeDevice != 0, "entry should still be reported as a device")
+	}
+
+	data := fs.entries["dev/fake0"].data
+	for i, b := range data {
+		assert.Equal(t, byte(0x55), b, "byte %d should have been overwritten", i)
+	}
+}
+
+func bytes8(b byte) []byte {
+	buf := make([]byte, 8)
+	for i := range buf {
+		buf[i] = b
+	}
+	return buf
+}
@@ -0,0 +1,24 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// blkGetSize64 is Linux's BLKGETSIZE64 ioctl request number, which reports a
+// block device's size in bytes; block devices read 0 from Stat's Size.
+const blkGetSize64 = 0x80081272
+
+// blockDeviceSize returns the size in bytes of the block device backing f.
+func blockDeviceSize(f *os.File) (int64, error) {
+	var size uint64
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), blkGetSize64, uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0, fmt.Errorf("BLKGETSIZE64: %w", errno)
+	}
+	return int64(size), nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// blockDeviceSize is only implemented on Linux (via BLKGETSIZE64); on other
+// platforms (DIOCGMEDIASIZE on BSD, DKIOCGETBLOCKCOUNT on Darwin) it's left
+// unimplemented for now.
+func blockDeviceSize(f *os.File) (int64, error) {
+	return 0, errors.New("determining block device size is only supported on linux")
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memFS is a minimal in-memory FS used by tests that only care about the
+// logic in Shredder, not real disk behavior (permissions, symlinks, ...
+// those are covered separately against osFS). It lets most table-driven
+// cases run without t.TempDir(). mu guards entries since shredAll's worker
+// pool drives it from multiple goroutines at once.
+type memFS struct {
+	mu      sync.Mutex
+	entries map[string]*memEntry
+	fsType  FSType
+}
+
+type memEntry struct {
+	mode os.FileMode
+	data []byte
+}
+
+func newMemFS() *memFS {
+	return &memFS{entries: make(map[string]*memEntry)}
+}
+
+func (m *memFS) addFile(name string, data []byte, mode os.FileMode) {
+	m.entries[name] = &memEntry{mode: mode, data: append([]byte(nil), data...)}
+}
+
+func (m *memFS) addDir(name string) {
+	m.entries[name] = &memEntry{mode: os.ModeDir | 0o700}
+}
+
+func (m *memFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[name]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return memFileInfo{name: name, entry: e}, nil
+}
+
+func (m *memFS) Lstat(name string) (os.FileInfo, error) {
+	return m.Stat(name)
+}
+
+func (m *memFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return &memFile{entry: e}, nil
+}
+
+func (m *memFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entries[name]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(m.entries, name)
+	return nil
+}
+
+// ReadDir returns name's immediate children. It only considers entries
+// registered directly one path segment below name, so tests must add each
+// directory level explicitly (addDir/addFile), the same way a real
+// directory tree would be built up one mkdir/create at a time.
+func (m *memFS) ReadDir(name string) ([]os.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dir, ok := m.entries[name]
+	if !ok || !dir.mode.IsDir() {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: os.ErrNotExist}
+	}
+
+	prefix := name + "/"
+	if name == "" {
+		prefix = ""
+	}
+
+	var out []os.DirEntry
+	for k, v := range m.entries {
+		if k == name || !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(k, prefix)
+		if strings.Contains(rest, "/") {
+			continue // not an immediate child
+		}
+		out = append(out, memDirEntry{name: rest, info: memFileInfo{name: rest, entry: v}})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+func (m *memFS) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[oldpath]
+	if !ok {
+		return &os.PathError{Op: "rename", Path: oldpath, Err: os.ErrNotExist}
+	}
+	delete(m.entries, oldpath)
+	m.entries[newpath] = e
+	return nil
+}
+
+func (m *memFS) SyncDir(name string) error { return nil }
+
+// DetectFSType lets tests simulate being on a particular filesystem;
+// defaults to FSTypeUnknown when unset.
+func (m *memFS) DetectFSType(path string) (FSType, error) {
+	if m.fsType == "" {
+		return FSTypeUnknown, nil
+	}
+	return m.fsType, nil
+}
+
+// memDirEntry implements os.DirEntry for a memEntry.
+type memDirEntry struct {
+	name string
+	info memFileInfo
+}
+
+func (e memDirEntry) Name() string               { return e.name }
+func (e memDirEntry) IsDir() bool                { return e.info.IsDir() }
+func (e memDirEntry) Type() os.FileMode          { return e.info.Mode().Type() }
+func (e memDirEntry) Info() (os.FileInfo, error) { return e.info, nil }
+
+// memFileInfo implements os.FileInfo for a memEntry.
+type memFileInfo struct {
+	name  string
+	entry *memEntry
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.entry.data)) }
+func (i memFileInfo) Mode() os.FileMode  { return i.entry.mode }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.entry.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
+
+// memFile implements File over a memEntry's data, writing in place like a
+// real file opened with O_WRONLY (no truncate, no append).
+type memFile struct {
+	entry *memEntry
+	pos   int64
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	end := f.pos + int64(len(p))
+	if end > int64(len(f.entry.data)) {
+		grown := make([]byte, end)
+		copy(grown, f.entry.data)
+		f.entry.data = grown
+	}
+	n := copy(f.entry.data[f.pos:end], p)
+	f.pos = end
+	return n, nil
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.pos >= int64(len(f.entry.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.entry.data[f.pos:])
+	f.pos += int64(n)
+	return n, nil
+}
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	var base int64
+	switch whence {
+	case 0:
+		base = 0
+	case 1:
+		base = f.pos
+	case 2:
+		base = int64(len(f.entry.data))
+	default:
+		return 0, fmt.Errorf("memFile: invalid whence %d", whence)
+	}
+	f.pos = base + offset
+	return f.pos, nil
+}
+
+func (f *memFile) Sync() error  { return nil }
+func (f *memFile) Close() error { return nil }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// File is the subset of *os.File that overwriteFile needs. It lets callers
+// plug in any backing store (a real file, an in-memory fake, some other
+// program's own VFS layer) as long as it can seek, write, sync and close.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Seeker
+	Sync() error
+	Close() error
+}
+
+// FS abstracts the filesystem operations Shredder needs. osFS is the
+// default, real-disk implementation; tests substitute a fake so they don't
+// have to touch t.TempDir() for every case.
+type FS interface {
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	Remove(name string) error
+	ReadDir(name string) ([]os.DirEntry, error)
+	Rename(oldpath, newpath string) error
+	// SyncDir fsyncs a directory's entries, so a preceding Rename or
+	// Remove within it survives a crash. It's a no-op on filesystems
+	// (real or fake) where that isn't meaningful.
+	SyncDir(name string) error
+	// DetectFSType reports the kind of filesystem path lives on, so
+	// ModeAuto can decide whether overwrite passes are trustworthy there.
+	DetectFSType(path string) (FSType, error)
+}
+
+// osFS is the default FS backed by the real operating system.
+type osFS struct{}
+
+func (osFS) Stat(name string) (os.FileInfo, error)  { return os.Stat(name) }
+func (osFS) Lstat(name string) (os.FileInfo, error) { return os.Lstat(name) }
+
+func (osFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (osFS) Remove(name string) error { return os.Remove(name) }
+
+func (osFS) ReadDir(name string) ([]os.DirEntry, error) { return os.ReadDir(name) }
+
+func (osFS) Rename(oldpath, newpath string) error { return os.Rename(oldpath, newpath) }
+
+func (osFS) SyncDir(name string) error {
+	d, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+	return d.Sync()
+}
+
+func (osFS) DetectFSType(path string) (FSType, error) { return detectFSType(path) }
@@ -0,0 +1,28 @@
+package main
+
+// FSType identifies the kind of filesystem a path lives on, as far as
+// Shredder needs to know: whether in-place overwrites are meaningful.
+type FSType string
+
+const (
+	FSTypeUnknown FSType = "unknown"
+	FSTypeBtrfs   FSType = "btrfs"
+	FSTypeZFS     FSType = "zfs"
+	FSTypeF2FS    FSType = "f2fs"
+	FSTypeTmpfs   FSType = "tmpfs"
+	FSTypeOverlay FSType = "overlay"
+	FSTypeNFS     FSType = "nfs"
+)
+
+// requiresCryptoShred reports whether t is a copy-on-write or
+// log-structured filesystem (or otherwise doesn't guarantee in-place
+// writes land on the original blocks), so ModeAuto should prefer
+// CryptoShred over repeated overwrite passes.
+func requiresCryptoShred(t FSType) bool {
+	switch t {
+	case FSTypeBtrfs, FSTypeZFS, FSTypeF2FS, FSTypeTmpfs, FSTypeOverlay, FSTypeNFS:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,43 @@
+//go:build linux
+
+package main
+
+import "syscall"
+
+// Filesystem magic numbers as reported by statfs(2)'s f_type field (see
+// statfs.h / the filesystems' own superblock definitions). Values are
+// compared as uint32 since f_type is declared as a signed 32-bit or 64-bit
+// field depending on architecture, but the magic numbers themselves only
+// ever occupy the low 32 bits.
+const (
+	magicBtrfs   = 0x9123683e
+	magicTmpfs   = 0x01021994
+	magicNFS     = 0x6969
+	magicOverlay = 0x794c7630
+	magicF2FS    = 0xf2f52010
+	magicZFS     = 0x2fc12fc1
+)
+
+func detectFSType(path string) (FSType, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return FSTypeUnknown, err
+	}
+
+	switch uint32(st.Type) {
+	case magicBtrfs:
+		return FSTypeBtrfs, nil
+	case magicTmpfs:
+		return FSTypeTmpfs, nil
+	case magicNFS:
+		return FSTypeNFS, nil
+	case magicOverlay:
+		return FSTypeOverlay, nil
+	case magicF2FS:
+		return FSTypeF2FS, nil
+	case magicZFS:
+		return FSTypeZFS, nil
+	default:
+		return FSTypeUnknown, nil
+	}
+}
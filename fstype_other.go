@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+// detectFSType has no portable implementation outside Linux's statfs(2);
+// callers fall back to treating the filesystem as unknown, which keeps
+// ModeAuto on the plain overwrite path.
+func detectFSType(path string) (FSType, error) {
+	return FSTypeUnknown, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import "io"
+
+// PassPattern produces the bytes written during a single overwrite pass.
+// Fill is called once per chunk rather than once per file so that patterns
+// such as Random can draw fresh bytes per chunk and repeating patterns
+// (the Gutmann triples) can stay aligned across chunk boundaries.
+type PassPattern interface {
+	// Fill writes len(dst) pattern bytes for the chunk starting at the
+	// given byte offset into the file. prev holds the bytes currently on
+	// disk at that offset (i.e. what the previous pass wrote there); it
+	// is only populated when NeedsPrevious reports true.
+	Fill(dst []byte, offset int64, prev []byte, rand io.Reader)
+	// NeedsPrevious reports whether overwriteFile must read the chunk's
+	// current on-disk bytes before calling Fill. Every pattern but
+	// Complement can ignore prev and avoid that extra read.
+	NeedsPrevious() bool
+}
+
+// repeatingPattern fills dst by repeating a short fixed byte sequence,
+// staying aligned to the absolute file offset so the pattern doesn't shift
+// between chunks.
+type repeatingPattern struct {
+	seq []byte
+}
+
+func (p repeatingPattern) Fill(dst []byte, offset int64, _ []byte, _ io.Reader) {
+	n := int64(len(p.seq))
+	for i := range dst {
+		dst[i] = p.seq[(offset+int64(i))%n]
+	}
+}
+
+func (repeatingPattern) NeedsPrevious() bool { return false }
+
+// randomPattern draws fresh bytes from the Shredder's random source on
+// every call; it's what overwriteFile has always done for every pass.
+type randomPattern struct{}
+
+func (randomPattern) Fill(dst []byte, _ int64, _ []byte, rand io.Reader) {
+	_, _ = io.ReadFull(rand, dst)
+}
+
+func (randomPattern) NeedsPrevious() bool { return false }
+
+// complementPattern writes the bitwise inverse of whatever the previous
+// pass left on disk at the same offset. Passes are applied to the whole
+// file in order, so by the time this pattern runs, the file itself already
+// holds the "previous pass" bytes it needs — no separate buffering required.
+type complementPattern struct{}
+
+func (complementPattern) Fill(dst []byte, _ int64, prev []byte, _ io.Reader) {
+	for i := range dst {
+		dst[i] = ^prev[i]
+	}
+}
+
+func (complementPattern) NeedsPrevious() bool { return true }
+
+// Named patterns for composing a Shredder.Patterns sequence.
+var (
+	// Zero fills each pass with 0x00 bytes.
+	Zero PassPattern = repeatingPattern{seq: []byte{0x00}}
+	// Ones fills each pass with 0xFF bytes.
+	Ones PassPattern = repeatingPattern{seq: []byte{0xFF}}
+	// Random draws fresh bytes from the Shredder's random source.
+	Random PassPattern = randomPattern{}
+	// Complement writes the bitwise inverse of the previous pass.
+	Complement PassPattern = complementPattern{}
+)
+
+// Fixed returns a pattern that fills every pass byte with b.
+func Fixed(b byte) PassPattern {
+	return repeatingPattern{seq: []byte{b}}
+}
+
+// triplet returns a pattern that repeats the 3-byte sequence a, b, c, used
+// by the Gutmann sequence to target specific magnetic encodings.
+func triplet(a, b, c byte) PassPattern {
+	return repeatingPattern{seq: []byte{a, b, c}}
+}
+
+// PresetSimpleRandom is three passes of random data — the original, fixed
+// behavior of this package before PassPattern existed.
+var PresetSimpleRandom = []PassPattern{Random, Random, Random}
+
+// PresetDoD3Pass follows the (informal) 3-pass DoD 5220.22-M-style policy:
+// all zeros, all ones, then random. Pair it with Shredder.Verify = true to
+// read back the final pass and confirm it matches what was written.
+var PresetDoD3Pass = []PassPattern{Zero, Ones, Random}
+
+// PresetGutmann is Peter Gutmann's 35-pass sequence: 4 random passes, 27
+// fixed/patterned passes targeting common magnetic encodings, then 4
+// trailing random passes.
+var PresetGutmann = buildGutmannSequence()
+
+func buildGutmannSequence() []PassPattern {
+	patterns := make([]PassPattern, 0, 35)
+
+	for i := 0; i < 4; i++ {
+		patterns = append(patterns, Random)
+	}
+
+	patterns = append(patterns,
+		Fixed(0x55), Fixed(0xAA),
+		triplet(0x92, 0x49, 0x24), triplet(0x49, 0x24, 0x92), triplet(0x24, 0x92, 0x49),
+		Fixed(0x00), Fixed(0x11), Fixed(0x22), Fixed(0x33), Fixed(0x44),
+		Fixed(0x55), Fixed(0x66), Fixed(0x77), Fixed(0x88), Fixed(0x99),
+		Fixed(0xAA), Fixed(0xBB), Fixed(0xCC), Fixed(0xDD), Fixed(0xEE), Fixed(0xFF),
+		triplet(0x92, 0x49, 0x24), triplet(0x49, 0x24, 0x92), triplet(0x24, 0x92, 0x49),
+		triplet(0x6D, 0xB6, 0xDB), triplet(0xB6, 0xDB, 0x6D), triplet(0xDB, 0x6D, 0xB6),
+	)
+
+	for i := 0; i < 4; i++ {
+		patterns = append(patterns, Random)
+	}
+
+	return patterns
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternsZeroOnesFixed(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("f.bin", []byte{1, 2, 3, 4}, 0o600)
+
+	s := NewShredder()
+	s.FS = fs
+	s.Patterns = []PassPattern{Zero, Ones, Fixed(0x42)}
+
+	if err := s.overwriteFile(context.Background(), "f.bin"); err != nil {
+		t.Fatalf("overwriteFile: %v", err)
+	}
+
+	data := fs.entries["f.bin"].data
+	for i, b := range data {
+		if b != 0x42 {
+			t.Fatalf("byte %d = %#x, want 0x42 (last pass written)", i, b)
+		}
+	}
+}
+
+func TestPatternComplementFollowsPreviousPass(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("f.bin", []byte{0, 0, 0, 0}, 0o600)
+
+	s := NewShredder()
+	s.FS = fs
+	s.Patterns = []PassPattern{Fixed(0x0F), Complement}
+
+	if err := s.overwriteFile(context.Background(), "f.bin"); err != nil {
+		t.Fatalf("overwriteFile: %v", err)
+	}
+
+	data := fs.entries["f.bin"].data
+	for i, b := range data {
+		if b != 0xF0 {
+			t.Fatalf("byte %d = %#x, want 0xF0 (complement of 0x0F)", i, b)
+		}
+	}
+}
+
+func TestPresetGutmannSequence(t *testing.T) {
+	assert.Len(t, PresetGutmann, 35, "Gutmann sequence must have 35 passes")
+	assert.Equal(t, Random, PresetGutmann[0], "first pass should be random")
+	assert.Equal(t, Random, PresetGutmann[3], "fourth pass should be random")
+	assert.Equal(t, Random, PresetGutmann[31], "32nd pass should be random")
+	assert.Equal(t, Random, PresetGutmann[34], "last pass should be random")
+}
+
+func TestVerifyDetectsMismatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "verify.bin")
+
+	s := NewShredder()
+	s.Patterns = []PassPattern{Zero}
+	s.Verify = true
+
+	if err := os.WriteFile(path, []byte{1, 2, 3}, 0o600); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if err := s.overwriteFile(context.Background(), path); err != nil {
+		t.Fatalf("overwriteFile with Verify=true on a well-behaved FS should not fail: %v", err)
+	}
+}
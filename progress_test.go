@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOverwriteReportsProgress verifies that Progress is called with
+// increasing bytesWritten and the expected pass/totalPasses for every chunk
+// written during an overwrite.
+func TestOverwriteReportsProgress(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("f.bin", make([]byte, 10), 0o600)
+
+	s := newTestShredder(fs, 0x11)
+	s.Patterns = []PassPattern{Zero, Ones}
+	s.BufSize = 4
+
+	var calls []struct {
+		written     int64
+		pass, total int
+	}
+	s.Progress = func(path string, bytesWritten, totalBytes int64, pass, totalPasses int) {
+		assert.Equal(t, "f.bin", path)
+		assert.Equal(t, int64(10), totalBytes)
+		calls = append(calls, struct {
+			written     int64
+			pass, total int
+		}{bytesWritten, pass, totalPasses})
+	}
+
+	if err := s.overwriteFile(context.Background(), "f.bin"); err != nil {
+		t.Fatalf("overwriteFile: %v", err)
+	}
+
+	if !assert.NotEmpty(t, calls, "expected at least one progress callback") {
+		return
+	}
+	assert.Equal(t, 2, calls[len(calls)-1].total, "totalPasses should equal len(Patterns)")
+
+	var prevWritten int64
+	var prevPass int
+	for _, c := range calls {
+		if c.pass != prevPass {
+			prevWritten = 0
+		}
+		assert.GreaterOrEqual(t, c.written, prevWritten, "bytesWritten must not go backwards within a pass")
+		prevWritten = c.written
+		prevPass = c.pass
+	}
+}
+
+// TestShredContextCanceledAborts verifies that a context canceled before
+// shredding starts aborts the overwrite promptly instead of running it to
+// completion.
+func TestShredContextCanceledAborts(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("f.bin", []byte{1, 2, 3, 4}, 0o600)
+
+	s := newTestShredder(fs, 0x22)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := s.ShredContext(ctx, "f.bin")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Since the context was already canceled, the file should be untouched.
+	data := fs.entries["f.bin"].data
+	assert.Equal(t, []byte{1, 2, 3, 4}, data)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SpecialFilePolicy controls how a recursive shred handles directory
+// entries that are neither regular files, directories, nor devices
+// (symlinks, sockets, fifos). Devices are shredded the same way regular
+// files are, not treated as special.
+type SpecialFilePolicy int
+
+const (
+	// SpecialSkip logs and skips special files, continuing the walk.
+	SpecialSkip SpecialFilePolicy = iota
+	// SpecialError aborts the walk as soon as a special file is found.
+	SpecialError
+)
+
+// RemoveMode controls how Shred unlinks a file once it has been
+// overwritten.
+type RemoveMode int
+
+const (
+	// RemoveStandard removes the file by its existing name, the same way
+	// os.Remove does.
+	RemoveStandard RemoveMode = iota
+	// RemoveWipeSync renames the file through a series of random names,
+	// fsyncing the parent directory after each rename, before removing
+	// it — see RemoveWithObfuscation.
+	RemoveWipeSync
+)
+
+// wipesyncRenames is the number of obfuscating renames RemoveWithObfuscation
+// performs before unlinking the file, matching GNU shred's default -u pass count.
+const wipesyncRenames = 10
+
+const alnumCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// shredDir walks dir, shredding every regular file it contains, descending
+// into subdirectories, and finally removing dir itself once it is empty.
+// Directories are removed bottom-up and are never themselves renamed or
+// overwritten — there's no file content to destroy, only entries to unlink.
+func (s *Shredder) shredDir(ctx context.Context, dir string) error {
+	entries, err := s.FS.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("readdir %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		full := filepath.Join(dir, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", full, err)
+		}
+
+		switch {
+		case info.IsDir():
+			if err := s.shredDir(ctx, full); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular(), info.Mode()&os.ModeDevice != 0:
+			if err := s.shredFile(ctx, full); err != nil {
+				return err
+			}
+		default:
+			if s.SpecialPolicy == SpecialError {
+				return fmt.Errorf("shred: %s is a special file", full)
+			}
+			if s.Logger != nil {
+				s.Logger.Printf("shred: skipping special file %s", full)
+			}
+		}
+	}
+
+	if err := s.FS.Remove(dir); err != nil {
+		return fmt.Errorf("remove %s: %w", dir, err)
+	}
+	return nil
+}
+
+// RemoveWithObfuscation unlinks path the way GNU shred's --remove=wipesync
+// does; it's equivalent to calling the context-aware version with
+// context.Background().
+func (s *Shredder) RemoveWithObfuscation(path string) error {
+	return s.removeWithObfuscation(context.Background(), path)
+}
+
+// removeWithObfuscation renames the file wipesyncRenames times, each rename
+// replacing one character of the name with a random alphanumeric character
+// and fsyncing the parent directory, so that the original filename isn't
+// recoverable from directory entries on journaled filesystems. It then
+// removes the final, fully obfuscated name.
+func (s *Shredder) removeWithObfuscation(ctx context.Context, path string) error {
+	dir := filepath.Dir(path)
+	name := filepath.Base(path)
+	current := path
+
+	for i := 0; i < wipesyncRenames; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name = s.obfuscateName(name)
+		next := filepath.Join(dir, name)
+
+		if err := s.FS.Rename(current, next); err != nil {
+			return fmt.Errorf("rename %s: %w", current, err)
+		}
+		if err := s.FS.SyncDir(dir); err != nil {
+			return fmt.Errorf("sync dir %s: %w", dir, err)
+		}
+		current = next
+	}
+
+	if err := s.FS.Remove(current); err != nil {
+		return fmt.Errorf("remove %s: %w", current, err)
+	}
+	return nil
+}
+
+// obfuscateName replaces one random character of name with a random
+// alphanumeric character, drawing randomness from s.Rand.
+func (s *Shredder) obfuscateName(name string) string {
+	if len(name) == 0 {
+		return name
+	}
+	b := []byte(name)
+	b[randIndex(s.Rand, len(b))] = randAlnumByte(s.Rand)
+	return string(b)
+}
+
+func randIndex(r io.Reader, n int) int {
+	var b [1]byte
+	_, _ = io.ReadFull(r, b[:])
+	return int(b[0]) % n
+}
+
+func randAlnumByte(r io.Reader) byte {
+	var b [1]byte
+	_, _ = io.ReadFull(r, b[:])
+	return alnumCharset[int(b[0])%len(alnumCharset)]
+}
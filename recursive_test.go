@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShredDirectoryWithoutRecursiveErrors(t *testing.T) {
+	fs := newMemFS()
+	fs.addDir("dir")
+	fs.addFile("dir/a.txt", []byte("data"), 0o600)
+
+	s := newTestShredder(fs, 0xAA)
+	err := s.Shred("dir")
+	assert.Error(t, err, "expected Shred on a directory without Recursive to fail")
+	_, statErr := fs.Stat("dir")
+	assert.NoError(t, statErr, "directory should be untouched")
+}
+
+func TestShredRecursiveRemovesTreeBottomUp(t *testing.T) {
+	fs := newMemFS()
+	fs.addDir("root")
+	fs.addFile("root/a.txt", []byte("one"), 0o600)
+	fs.addDir("root/sub")
+	fs.addFile("root/sub/b.txt", []byte("two"), 0o600)
+
+	s := newTestShredder(fs, 0xAA)
+	s.Recursive = true
+
+	err := s.Shred("root")
+	assert.NoError(t, err, "recursive Shred should succeed")
+
+	for _, p := range []string{"root/a.txt", "root/sub/b.txt", "root/sub", "root"} {
+		_, statErr := fs.Stat(p)
+		assert.True(t, statErr != nil, "expected %s to be removed", p)
+	}
+}
+
+func TestShredRecursiveSpecialFilePolicy(t *testing.T) {
+	fs := newMemFS()
+	fs.addDir("root")
+	fs.entries["root/link"] = &memEntry{mode: os.ModeSymlink | 0o777}
+
+	skip := newTestShredder(fs, 0xAA)
+	skip.Recursive = true
+	skip.SpecialPolicy = SpecialSkip
+	assert.NoError(t, skip.Shred("root"), "skip policy should not fail on a special file")
+	_, statErr := fs.Stat("root/link")
+	assert.NoError(t, statErr, "skipped special file should remain")
+
+	fs2 := newMemFS()
+	fs2.addDir("root")
+	fs2.entries["root/link"] = &memEntry{mode: os.ModeSymlink | 0o777}
+	fail := newTestShredder(fs2, 0xAA)
+	fail.Recursive = true
+	fail.SpecialPolicy = SpecialError
+	assert.Error(t, fail.Shred("root"), "error policy should fail on a special file")
+}
+
+func TestRemoveWithObfuscationRenamesThenRemoves(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("secret.txt", []byte("data"), 0o600)
+
+	s := newTestShredder(fs, 0x01)
+	s.RemoveMode = RemoveWipeSync
+
+	err := s.Shred("secret.txt")
+	assert.NoError(t, err, "wipesync Shred should succeed")
+
+	_, statErr := fs.Stat("secret.txt")
+	assert.Error(t, statErr, "original name should no longer exist")
+	assert.Empty(t, fs.entries, "no renamed entry should survive the final removal")
+}
@@ -1,110 +1,161 @@
 package main
 
 import (
-	"crypto/rand"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
 	"os"
+	"os/signal"
+	"sync"
 )
 
-const shredPasses = 3
-const shredBufSize = 64 * 1024 // 64 KB
-
-// Make the random source injectable so that we can use a deterministic source in tests.
-var randomSource io.Reader = rand.Reader
-
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <file1> [file2 ...]\n", os.Args[0])
+	var recursive bool
+	flag.BoolVar(&recursive, "r", false, "shred directories recursively")
+	flag.BoolVar(&recursive, "recursive", false, "shred directories recursively")
+
+	special := flag.String("special", "skip", "policy for special files found while recursing: skip or error")
+	remove := flag.String("remove", "standard", "file removal mode: standard or wipesync")
+	mode := flag.String("mode", "overwrite", "destruction mode: overwrite, crypto or auto")
+	sparse := flag.String("sparse", "dense", "sparse file handling: dense (overwrite every logical byte) or keep (only overwrite allocated extents)")
+	preset := flag.String("preset", "random", "overwrite pattern preset: random, dod3 or gutmann")
+	verify := flag.Bool("verify", false, "read back the final pass and confirm it matches what was written")
+	jobs := flag.Int("jobs", 1, "number of files to shred concurrently")
+	progress := flag.Bool("progress", false, "print per-file progress while shredding")
+
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-r] [--special=skip|error] [--remove=standard|wipesync] [--mode=overwrite|crypto|auto] [--sparse=dense|keep] [--preset=random|dod3|gutmann] [--verify] [--jobs=N] [--progress] <file1> [file2 ...]\n", os.Args[0])
 		os.Exit(1)
 	}
-	exitCode := 0
-	for _, path := range os.Args[1:] {
-		fmt.Printf("Shredding %s...\n", path)
-		if err := Shred(path); err != nil {
-			fmt.Fprintf(os.Stderr, "Error shredding %s: %v\n", path, err)
-			exitCode = 1
-		} else {
-			fmt.Fprintf(os.Stdout, "Shredded successfully: %s\n", path)
-		}
+	if *jobs < 1 {
+		fmt.Fprintf(os.Stderr, "invalid --jobs value %d (want >= 1)\n", *jobs)
+		os.Exit(1)
 	}
-	os.Exit(exitCode)
-}
 
-// Shred:
-// - Overwrites the file with random data for shredPasses passes via overwriteFile.
-// - Then removes (deletes) the file.
-func Shred(path string) error {
-	if err := overwriteFile(path, shredPasses); err != nil {
-		return err
-	}
+	s := NewShredder()
+	s.Recursive = recursive
 
-	if err := os.Remove(path); err != nil {
-		return fmt.Errorf("remove %s: %w", path, err)
+	switch *special {
+	case "skip":
+		s.SpecialPolicy = SpecialSkip
+	case "error":
+		s.SpecialPolicy = SpecialError
+	default:
+		fmt.Fprintf(os.Stderr, "invalid --special value %q (want skip or error)\n", *special)
+		os.Exit(1)
 	}
-	return nil
-}
 
-// overwriteFile:
-// - Retrieves the size of the given file.
-// - Overwrites the file from start to end with data from randomSource for the given number of passes.
-// - DOES NOT delete the file.
-// Shred calls this function and then deletes the file afterwards.
-func overwriteFile(path string, passes int) error {
-	info, err := os.Stat(path)
-	if err != nil {
-		return fmt.Errorf("stat %s: %w", path, err)
+	switch *remove {
+	case "standard":
+		s.RemoveMode = RemoveStandard
+	case "wipesync":
+		s.RemoveMode = RemoveWipeSync
+	default:
+		fmt.Fprintf(os.Stderr, "invalid --remove value %q (want standard or wipesync)\n", *remove)
+		os.Exit(1)
 	}
 
-	if !info.Mode().IsRegular() {
-		return fmt.Errorf("shred: %s is not a regular file", path)
+	switch *mode {
+	case "overwrite":
+		s.Mode = ModeOverwrite
+	case "crypto":
+		s.Mode = ModeCrypto
+	case "auto":
+		s.Mode = ModeAuto
+	default:
+		fmt.Fprintf(os.Stderr, "invalid --mode value %q (want overwrite, crypto or auto)\n", *mode)
+		os.Exit(1)
 	}
 
-	size := info.Size()
-	if size < 0 {
-		return fmt.Errorf("shred: invalid file size for %s", path)
+	switch *sparse {
+	case "dense":
+		s.Sparse = SparseDense
+	case "keep":
+		s.Sparse = SparseKeep
+	default:
+		fmt.Fprintf(os.Stderr, "invalid --sparse value %q (want dense or keep)\n", *sparse)
+		os.Exit(1)
 	}
 
-	// Open the file in write-only mode without truncating it.
-	f, err := os.OpenFile(path, os.O_WRONLY, 0)
-	if err != nil {
-		return fmt.Errorf("open %s: %w", path, err)
+	switch *preset {
+	case "random":
+		s.Patterns = append([]PassPattern(nil), PresetSimpleRandom...)
+	case "dod3":
+		s.Patterns = append([]PassPattern(nil), PresetDoD3Pass...)
+	case "gutmann":
+		s.Patterns = append([]PassPattern(nil), PresetGutmann...)
+	default:
+		fmt.Fprintf(os.Stderr, "invalid --preset value %q (want random, dod3 or gutmann)\n", *preset)
+		os.Exit(1)
 	}
-	// Ensure the file descriptor is closed even if we return early due to an error.
-	defer func() { _ = f.Close() }()
-
-	buf := make([]byte, shredBufSize)
-
-	for pass := 0; pass < passes; pass++ {
-		if _, err := f.Seek(0, 0); err != nil {
-			return fmt.Errorf("seek %s: %w", path, err)
+	s.Verify = *verify
+
+	var stdout sync.Mutex
+	if *progress {
+		s.Progress = func(path string, bytesWritten, totalBytes int64, pass, totalPasses int) {
+			stdout.Lock()
+			defer stdout.Unlock()
+			fmt.Printf("\r%s: pass %d/%d, %d/%d bytes", path, pass, totalPasses, bytesWritten, totalBytes)
+			if bytesWritten == totalBytes && pass == totalPasses {
+				fmt.Println()
+			}
 		}
+	}
 
-		var written int64
-		for written < size {
-			chunk := buf
-			remaining := size - written
-			if remaining < int64(len(chunk)) {
-				chunk = chunk[:remaining]
-			}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-			// In tests, we override randomSource with a fake reader to make the behavior deterministic.
-			if _, err := io.ReadFull(randomSource, chunk); err != nil {
-				return fmt.Errorf("fill random (pass %d): %w", pass+1, err)
-			}
+	exitCode := shredAll(ctx, s, args, *jobs, &stdout)
+	os.Exit(exitCode)
+}
 
-			n, err := f.Write(chunk)
-			if err != nil {
-				return fmt.Errorf("write pass %d: %w", pass+1, err)
+// shredAll shreds every path in args using up to jobs worker goroutines,
+// returning a process exit code. ctx is propagated to ShredContext so a
+// signal (e.g. Ctrl-C) aborts in-flight shreds promptly instead of only
+// stopping the dispatch of new ones.
+func shredAll(ctx context.Context, s *Shredder, args []string, jobs int, stdout *sync.Mutex) int {
+	paths := make(chan string)
+	var failed int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				stdout.Lock()
+				fmt.Printf("Shredding %s...\n", path)
+				stdout.Unlock()
+
+				err := s.ShredContext(ctx, path)
+
+				stdout.Lock()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error shredding %s: %v\n", path, err)
+					failed = 1
+				} else {
+					fmt.Fprintf(os.Stdout, "Shredded successfully: %s\n", path)
+				}
+				stdout.Unlock()
 			}
-			written += int64(n)
-		}
+		}()
+	}
 
-		if err := f.Sync(); err != nil {
-			return fmt.Errorf("sync %s: %w", path, err)
+	for _, path := range args {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			break
 		}
+		paths <- path
 	}
+	close(paths)
+	wg.Wait()
 
-	// Actual close is handled by the deferred function above.
-	return nil
+	if failed != 0 {
+		return 1
+	}
+	return 0
 }
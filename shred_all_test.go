@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestShredAllConcurrentJobsShredsEveryPath verifies that shredAll, with
+// jobs > 1, shreds every path it's given and returns exit code 0 when all
+// of them succeed.
+func TestShredAllConcurrentJobsShredsEveryPath(t *testing.T) {
+	fs := newMemFS()
+	names := []string{"a.bin", "b.bin", "c.bin", "d.bin"}
+	for _, name := range names {
+		fs.addFile(name, []byte{0x11, 0x11, 0x11, 0x11}, 0o600)
+	}
+
+	s := newTestShredder(fs, 0xAA)
+	s.Patterns = []PassPattern{Fixed(0x55)}
+
+	var stdout sync.Mutex
+	code := shredAll(context.Background(), s, names, 2, &stdout)
+
+	assert.Equal(t, 0, code)
+	for _, name := range names {
+		if _, ok := fs.entries[name]; assert.False(t, ok, "%s should have been removed", name) {
+			continue
+		}
+	}
+}
+
+// TestShredAllReportsFailureExitCode verifies that shredAll returns exit
+// code 1 when any path fails to shred, even if the rest succeed.
+func TestShredAllReportsFailureExitCode(t *testing.T) {
+	fs := newMemFS()
+	fs.addFile("ok.bin", []byte{0x11, 0x11}, 0o600)
+	// "missing.bin" is never added, so ShredContext fails on it with ENOENT.
+
+	s := newTestShredder(fs, 0xAA)
+
+	var stdout sync.Mutex
+	code := shredAll(context.Background(), s, []string{"ok.bin", "missing.bin"}, 2, &stdout)
+
+	assert.Equal(t, 1, code)
+	_, ok := fs.entries["ok.bin"]
+	assert.False(t, ok, "ok.bin should still have been shredded despite missing.bin failing")
+}
+
+// TestShredAllStopsDispatchOnCanceledContext verifies that shredAll's
+// dispatch loop checks ctx before sending each path to a worker, so a
+// context canceled up front stops it from consuming any of args. (Canceling
+// mid-dispatch instead would race against the worker pool's own timing, so
+// this pins down the same code path deterministically: the loop's ctx.Err()
+// check, not the workers, is what stops further consumption.)
+func TestShredAllStopsDispatchOnCanceledContext(t *testing.T) {
+	fs := newMemFS()
+	names := []string{"a.bin", "b.bin", "c.bin"}
+	for _, name := range names {
+		fs.addFile(name, []byte{0x11, 0x11}, 0o600)
+	}
+
+	s := newTestShredder(fs, 0xAA)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var stdout sync.Mutex
+	code := shredAll(ctx, s, names, 1, &stdout)
+
+	assert.Equal(t, 0, code, "no path was even attempted, so nothing failed")
+	for _, name := range names {
+		_, ok := fs.entries[name]
+		assert.True(t, ok, "%s should not have been consumed once ctx was canceled", name)
+	}
+}
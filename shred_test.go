@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -14,7 +15,7 @@ import (
 // ---- Helper types / functions ----
 
 // fakeReader: an io.Reader that fills the buffer with the same byte value.
-// This makes randomSource deterministic in tests.
+// This makes the random source deterministic in tests.
 type fakeReader struct {
 	b byte
 }
@@ -26,20 +27,22 @@ func (f *fakeReader) Read(p []byte) (int, error) {
 	return len(p), nil
 }
 
-// withFakeRandom temporarily overrides randomSource with a fakeReader
-// that always returns the given byte, then restores the original source.
-func withFakeRandom(b byte, fn func()) {
-	prev := randomSource
-	randomSource = &fakeReader{b: b}
-	defer func() { randomSource = prev }()
-	fn()
+// newTestShredder returns a Shredder wired to the given FS and a
+// deterministic random source, ready for use in tests.
+func newTestShredder(fs FS, b byte) *Shredder {
+	s := NewShredder()
+	s.FS = fs
+	s.Rand = &fakeReader{b: b}
+	return s
 }
 
 // ---- Table-driven Shred tests ----
 
 func TestShred(t *testing.T) {
 	type args struct {
-		setup func(t *testing.T, tmpDir string) string
+		// fs builds the FS under test (typically an in-memory fake) and
+		// returns it along with the path to exercise Shred on.
+		fs func(t *testing.T) (FS, string)
 	}
 
 	tests := []struct {
@@ -47,17 +50,14 @@ func TestShred(t *testing.T) {
 		args       args
 		wantErr    bool
 		wantExists bool // whether the path should still exist after Shred
-		skipOnWin  bool // skip on Windows for permission-specific tests
 	}{
 		{
 			name: "RegularFileRemoved",
 			args: args{
-				setup: func(t *testing.T, tmpDir string) string {
-					path := filepath.Join(tmpDir, "file.txt")
-					if err := os.WriteFile(path, []byte("secret data"), 0o600); err != nil {
-						t.Fatalf("failed to create temp file: %v", err)
-					}
-					return path
+				fs: func(t *testing.T) (FS, string) {
+					fs := newMemFS()
+					fs.addFile("file.txt", []byte("secret data"), 0o600)
+					return fs, "file.txt"
 				},
 			},
 			wantErr:    false,
@@ -66,12 +66,10 @@ func TestShred(t *testing.T) {
 		{
 			name: "EmptyFileRemoved",
 			args: args{
-				setup: func(t *testing.T, tmpDir string) string {
-					path := filepath.Join(tmpDir, "empty.txt")
-					if err := os.WriteFile(path, []byte{}, 0o600); err != nil {
-						t.Fatalf("failed to create empty file: %v", err)
-					}
-					return path
+				fs: func(t *testing.T) (FS, string) {
+					fs := newMemFS()
+					fs.addFile("empty.txt", []byte{}, 0o600)
+					return fs, "empty.txt"
 				},
 			},
 			wantErr:    false,
@@ -80,9 +78,9 @@ func TestShred(t *testing.T) {
 		{
 			name: "NonExistingFile",
 			args: args{
-				setup: func(t *testing.T, tmpDir string) string {
-					// Intentionally do NOT create the file
-					return filepath.Join(tmpDir, "does-not-exist")
+				fs: func(t *testing.T) (FS, string) {
+					// Intentionally do NOT register the file
+					return newMemFS(), "does-not-exist"
 				},
 			},
 			wantErr:    true,
@@ -91,52 +89,28 @@ func TestShred(t *testing.T) {
 		{
 			name: "DirectoryIsNotRegularFile",
 			args: args{
-				setup: func(t *testing.T, tmpDir string) string {
-					dir := filepath.Join(tmpDir, "subdir")
-					if err := os.Mkdir(dir, 0o700); err != nil {
-						t.Fatalf("failed to create directory: %v", err)
-					}
-					return dir
+				fs: func(t *testing.T) (FS, string) {
+					fs := newMemFS()
+					fs.addDir("subdir")
+					return fs, "subdir"
 				},
 			},
 			wantErr:    true,
 			wantExists: true, // directory should remain, Shred should fail
 		},
-		{
-			name:      "ReadOnlyFile",
-			skipOnWin: true, // Windows permission semantics are different, focus on Unix
-			args: args{
-				setup: func(t *testing.T, tmpDir string) string {
-					path := filepath.Join(tmpDir, "readonly.txt")
-					if err := os.WriteFile(path, []byte("cannot write"), 0o600); err != nil {
-						t.Fatalf("failed to create temp file: %v", err)
-					}
-					if err := os.Chmod(path, 0o400); err != nil {
-						t.Fatalf("failed to chmod file: %v", err)
-					}
-					return path
-				},
-			},
-			wantErr:    true, // we expect Shred to fail because it cannot write
-			wantExists: true, // file should still exist
-		},
 	}
 
 	for _, tt := range tests {
 		tt := tt // capture range variable
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.skipOnWin && runtime.GOOS == "windows" {
-				t.Skip("skipping permission-specific test on Windows")
-			}
-
-			tmpDir := t.TempDir()
-			path := tt.args.setup(t, tmpDir)
+			fs, path := tt.args.fs(t)
+			s := newTestShredder(fs, 0xAA)
 
 			t.Logf("=== Starting subtest: %q ===", tt.name)
 			t.Logf("Path under test: %s", path)
 			t.Logf("Expected: wantErr=%v, wantExists=%v", tt.wantErr, tt.wantExists)
 
-			err := Shred(path)
+			err := s.Shred(path)
 
 			// Check error expectation
 			if tt.wantErr {
@@ -156,7 +130,7 @@ func TestShred(t *testing.T) {
 			}
 
 			// Check existence
-			_, statErr := os.Stat(path)
+			_, statErr := fs.Stat(path)
 			exists := !os.IsNotExist(statErr)
 			t.Logf("Filesystem check: exists=%v (statErr=%v)", exists, statErr)
 
@@ -179,10 +153,34 @@ func TestShred(t *testing.T) {
 	}
 }
 
+// TestShredReadOnlyFile exercises real OS permission semantics, which the
+// in-memory fake does not model, so it runs against osFS directly.
+func TestShredReadOnlyFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping permission-specific test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "readonly.txt")
+	if err := os.WriteFile(path, []byte("cannot write"), 0o600); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	if err := os.Chmod(path, 0o400); err != nil {
+		t.Fatalf("failed to chmod file: %v", err)
+	}
+
+	s := NewShredder()
+	err := s.Shred(path)
+	assert.Error(t, err, "expected error but got nil for path=%s", path)
+
+	_, statErr := os.Stat(path)
+	assert.False(t, os.IsNotExist(statErr), "expected path to still exist: %s", path)
+}
+
 // ---- Deterministic random + overwrite tests ----
 
-// This test verifies that overwriteFile uses randomSource and
-// actually overwrites the entire file content.
+// This test verifies that overwriteFile uses the Shredder's random source
+// and actually overwrites the entire file content.
 func TestOverwriteDeterministicRandom(t *testing.T) {
 	t.Log("Starting TestOverwriteDeterministicRandom: verifying deterministic overwrite using fake random source")
 
@@ -196,13 +194,14 @@ func TestOverwriteDeterministicRandom(t *testing.T) {
 	}
 	t.Logf("Created test file: %s (size=%d bytes)", path, len(original))
 
-	withFakeRandom(0xAA, func() {
-		t.Log("Using fake random source that always returns 0xAA")
-		err := overwriteFile(path, 1)
-		if assert.NoError(t, err, "overwriteFile returned error") {
-			t.Log("overwriteFile completed without error")
-		}
-	})
+	s := NewShredder()
+	s.Rand = &fakeReader{b: 0xAA}
+	s.Patterns = []PassPattern{Random}
+	t.Log("Using fake random source that always returns 0xAA")
+	err := s.overwriteFile(context.Background(), path)
+	if assert.NoError(t, err, "overwriteFile returned error") {
+		t.Log("overwriteFile completed without error")
+	}
 
 	data, err := os.ReadFile(path)
 	if assert.NoError(t, err, "failed to read overwritten file") {
@@ -226,9 +225,9 @@ func TestOverwriteDeterministicRandom(t *testing.T) {
 // - we create a target file and a symlink pointing to it
 // - we call Shred on the symlink path
 // Expected behavior in this implementation:
-//   * Shred follows the symlink and overwrites the target file
-//   * Shred removes the symlink path
-//   * The target file still exists but its content is overwritten
+//   - Shred follows the symlink and overwrites the target file
+//   - Shred removes the symlink path
+//   - The target file still exists but its content is overwritten
 func TestShredSymlink(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("symlink test skipped on Windows (requires special privileges)")
@@ -251,17 +250,16 @@ func TestShredSymlink(t *testing.T) {
 	}
 	t.Logf("Created symlink: %s -> %s", link, target)
 
-	// Use deterministic fake random source
-	withFakeRandom(0xBB, func() {
-		t.Log("Using fake random source that always returns 0xBB")
-		err := Shred(link)
-		if assert.NoError(t, err, "Shred(symlink) returned error") {
-			t.Log("Shred on symlink completed without error")
-		}
-	})
+	s := NewShredder()
+	s.Rand = &fakeReader{b: 0xBB}
+	t.Log("Using fake random source that always returns 0xBB")
+	err := s.Shred(link)
+	if assert.NoError(t, err, "Shred(symlink) returned error") {
+		t.Log("Shred on symlink completed without error")
+	}
 
 	// Symlink should be removed
-	_, err := os.Stat(link)
+	_, err = os.Stat(link)
 	if assert.True(t, os.IsNotExist(err), "expected symlink to be removed") {
 		t.Log("Symlink was removed as expected")
 	} else {
@@ -320,8 +318,9 @@ func TestShredBigFile(t *testing.T) {
 	}
 	t.Logf("Big file created successfully, total written bytes = %d", written)
 
+	s := NewShredder()
 	start := time.Now()
-	err = Shred(path)
+	err = s.Shred(path)
 	duration := time.Since(start)
 
 	if assert.NoError(t, err, "Shred(big file) returned error") {
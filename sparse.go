@@ -0,0 +1,17 @@
+package main
+
+// SparseMode controls how overwriteFile handles sparse regular files —
+// those whose on-disk allocation (stat.Blocks*512) is smaller than their
+// logical size.
+type SparseMode int
+
+const (
+	// SparseDense writes every logical byte, including holes, the same
+	// way overwriteFile has always behaved.
+	SparseDense SparseMode = iota
+	// SparseKeep only overwrites the extents the filesystem already has
+	// allocated, using SEEK_DATA/SEEK_HOLE to find them, so holes aren't
+	// turned into allocated zero blocks. Supported on Linux only; it
+	// behaves like SparseDense elsewhere.
+	SparseKeep
+)
@@ -0,0 +1,59 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// Linux lseek whence values for finding allocated/unallocated regions,
+// absent from the io package and the portable parts of syscall.
+const (
+	seekData = 3
+	seekHole = 4
+)
+
+// isSparseFile reports whether info's underlying file occupies fewer blocks
+// on disk than its logical size implies, the same test GNU coreutils uses.
+func isSparseFile(info os.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	return st.Blocks*512 < info.Size()
+}
+
+// dataExtents returns the [start, end) byte ranges of f that are actually
+// allocated, found via SEEK_DATA/SEEK_HOLE. f's offset is restored to 0
+// before returning.
+func dataExtents(f *os.File, size int64) ([][2]int64, error) {
+	fd := int(f.Fd())
+	var extents [][2]int64
+
+	offset := int64(0)
+	for offset < size {
+		dataStart, err := syscall.Seek(fd, offset, seekData)
+		if err != nil {
+			if err == syscall.ENXIO {
+				break // no more data after offset
+			}
+			return nil, fmt.Errorf("seek_data at %d: %w", offset, err)
+		}
+
+		holeStart, err := syscall.Seek(fd, dataStart, seekHole)
+		if err != nil {
+			return nil, fmt.Errorf("seek_hole at %d: %w", dataStart, err)
+		}
+
+		extents = append(extents, [2]int64{dataStart, holeStart})
+		offset = holeStart
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek %s: %w", f.Name(), err)
+	}
+	return extents, nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import "os"
+
+// isSparseFile always reports false outside Linux; SEEK_DATA/SEEK_HOLE
+// detection isn't implemented for other platforms yet.
+func isSparseFile(info os.FileInfo) bool { return false }
+
+// dataExtents treats the whole file as one allocated extent outside Linux,
+// the same as SparseDense would.
+func dataExtents(f *os.File, size int64) ([][2]int64, error) {
+	return [][2]int64{{0, size}}, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSparseKeepOnlyOverwritesAllocatedExtents creates a file with a large
+// hole and a small allocated tail, then shreds it with SparseKeep. Bytes
+// inside the hole must be left untouched (never allocated by the shred
+// itself), while the allocated tail must be overwritten as usual.
+func TestSparseKeepOnlyOverwritesAllocatedExtents(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("sparse file support is linux-only")
+	}
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "sparse.bin")
+
+	const holeSize = 16 << 20 // 16 MB hole
+	const tailSize = 4096
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := f.Truncate(holeSize + tailSize); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	if _, err := f.WriteAt(make([]byte, tailSize), holeSize); err != nil {
+		t.Fatalf("write tail: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if !isSparseFile(info) {
+		t.Skip("filesystem backing t.TempDir() does not allocate sparse files here")
+	}
+
+	s := NewShredder()
+	s.Patterns = []PassPattern{Fixed(0x55)}
+	s.Sparse = SparseKeep
+
+	if err := s.overwriteFile(context.Background(), path); err != nil {
+		t.Fatalf("overwriteFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+
+	for i := int64(0); i < holeSize; i += 4096 {
+		assert.Equal(t, byte(0), data[i], "hole byte at %d should be left untouched", i)
+	}
+	for _, b := range data[holeSize:] {
+		assert.Equal(t, byte(0x55), b, "allocated tail should be overwritten")
+	}
+}
+
+// TestDataExtentsFindsDataAfterHole verifies dataExtents reports the
+// allocated tail of a sparse file and restores the file's offset to 0.
+func TestDataExtentsFindsDataAfterHole(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("sparse file support is linux-only")
+	}
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "sparse.bin")
+
+	const holeSize = 16 << 20
+	const tailSize = 4096
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := f.Truncate(holeSize + tailSize); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	if _, err := f.WriteAt([]byte{1}, holeSize); err != nil {
+		t.Fatalf("write tail: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if !isSparseFile(info) {
+		t.Skip("filesystem backing t.TempDir() does not allocate sparse files here")
+	}
+
+	extents, err := dataExtents(f, holeSize+tailSize)
+	if err != nil {
+		t.Fatalf("dataExtents: %v", err)
+	}
+	if !assert.NotEmpty(t, extents, "expected at least one data extent") {
+		return
+	}
+	last := extents[len(extents)-1]
+	assert.GreaterOrEqual(t, last[0], int64(holeSize-4096), "allocated extent should start at or near the written tail")
+	assert.Equal(t, int64(holeSize+tailSize), last[1], "allocated extent should run to end of file")
+
+	pos, err := f.Seek(0, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(0), pos, "dataExtents should restore the file offset to 0")
+	}
+}